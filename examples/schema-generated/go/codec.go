@@ -0,0 +1,89 @@
+package schema
+
+import "encoding/json"
+
+// Codec encodes and decodes schema values to and from a particular wire
+// format. Implementations must be safe for concurrent use, since a
+// single Codec instance is shared across RPC requests.
+type Codec interface {
+	// Name identifies the codec for WebSocket subprotocol negotiation,
+	// e.g. "json".
+	Name() string
+	// MIME is the Content-Type / Accept header value that selects this
+	// codec over HTTP, e.g. "application/json".
+	MIME() string
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+// codecsByName and codecsByMIME are the registries every codec in this
+// package installs itself into via registerCodec. The rpc package
+// consults these when negotiating a Content-Type, Accept header, or
+// WebSocket subprotocol.
+//
+// Note: application/x-protobuf is not registered here. See
+// ../proto/schema.proto for the hand-written Node/HyperEdge proto
+// definitions and why the Go codec isn't implemented yet.
+var (
+	codecsByName = map[string]Codec{}
+	codecsByMIME = map[string]Codec{}
+)
+
+func registerCodec(c Codec) {
+	codecsByName[c.Name()] = c
+	codecsByMIME[c.MIME()] = c
+}
+
+// CodecByName returns the registered codec for a WebSocket subprotocol
+// name, or nil if none is registered under that name.
+func CodecByName(name string) Codec {
+	return codecsByName[name]
+}
+
+// CodecByMIME returns the registered codec for a Content-Type / Accept
+// MIME value, or nil if none is registered under that MIME type.
+func CodecByMIME(mime string) Codec {
+	return codecsByMIME[mime]
+}
+
+// DefaultCodec is the JSON codec, matching the historical behavior of
+// SerializeNode/SerializeHyperEdge before codecs were pluggable.
+var DefaultCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) MIME() string { return "application/json" }
+
+func (jsonCodec) Encode(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Decode(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+func init() {
+	registerCodec(jsonCodec{})
+}
+
+// SerializeNode encodes n using DefaultCodec.
+func SerializeNode(n Node) ([]byte, error) {
+	return DefaultCodec.Encode(n)
+}
+
+// DeserializeNode decodes data into a Node using DefaultCodec.
+func DeserializeNode(data []byte) (Node, error) {
+	var n Node
+	err := DefaultCodec.Decode(data, &n)
+	return n, err
+}
+
+// SerializeHyperEdge encodes e using DefaultCodec.
+func SerializeHyperEdge(e HyperEdge) ([]byte, error) {
+	return DefaultCodec.Encode(e)
+}
+
+// DeserializeHyperEdge decodes data into a HyperEdge using DefaultCodec.
+func DeserializeHyperEdge(data []byte) (HyperEdge, error) {
+	var e HyperEdge
+	err := DefaultCodec.Decode(data, &e)
+	return e, err
+}
@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// cborDecMode decodes into map[string]interface{} rather than the
+// library default of map[interface{}]interface{}, since transcode's
+// decode-to-any/re-encode-as-JSON round trip requires string map keys.
+var cborDecMode = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+	if err != nil {
+		panic(fmt.Sprintf("schema: build cbor decode mode: %v", err))
+	}
+	return mode
+}()
+
+type cborCodec struct{}
+
+func (cborCodec) Name() string { return "cbor" }
+
+func (cborCodec) MIME() string { return "application/cbor" }
+
+func (cborCodec) Encode(v any) ([]byte, error) { return cbor.Marshal(v) }
+
+func (cborCodec) Decode(data []byte, v any) error { return cborDecMode.Unmarshal(data, v) }
+
+func init() {
+	registerCodec(cborCodec{})
+}
@@ -0,0 +1,75 @@
+package schema
+
+import "testing"
+
+var allCodecs = []Codec{jsonCodec{}, msgpackCodec{}, cborCodec{}}
+
+// FuzzNodeRoundtrip checks that every registered codec round-trips a
+// Node, including the fixed [4]float64 Position, without loss.
+func FuzzNodeRoundtrip(f *testing.F) {
+	f.Add("1111-2222", "Alice", 1.0, 2.0, 3.0, 4.0)
+	f.Add("", "", 0.0, 0.0, 0.0, 0.0)
+	f.Fuzz(func(t *testing.T, id, title string, x, y, z, w float64) {
+		n := Node{Id: id, Title: title, Position: [4]float64{x, y, z, w}}
+		for _, c := range allCodecs {
+			data, err := c.Encode(n)
+			if err != nil {
+				t.Fatalf("%s: encode error: %v", c.Name(), err)
+			}
+			var got Node
+			if err := c.Decode(data, &got); err != nil {
+				t.Fatalf("%s: decode error: %v", c.Name(), err)
+			}
+			if got.Id != n.Id || got.Title != n.Title || got.Position != n.Position {
+				t.Fatalf("%s: roundtrip mismatch: got %+v, want %+v", c.Name(), got, n)
+			}
+		}
+	})
+}
+
+// FuzzHyperEdgeRoundtrip checks that every registered codec round-trips
+// a HyperEdge, including the omitempty behavior of Label when it is nil.
+func FuzzHyperEdgeRoundtrip(f *testing.F) {
+	f.Add("he1", "n1;n2", "kind-a")
+	f.Add("he2", "", "")
+	f.Fuzz(func(t *testing.T, id, nodeCSV, kind string) {
+		var label *Label
+		if kind != "" {
+			label = &Label{Kind: kind}
+		}
+		e := HyperEdge{Id: id, Nodes: splitCSV(nodeCSV), Label: label}
+		for _, c := range allCodecs {
+			data, err := c.Encode(e)
+			if err != nil {
+				t.Fatalf("%s: encode error: %v", c.Name(), err)
+			}
+			var got HyperEdge
+			if err := c.Decode(data, &got); err != nil {
+				t.Fatalf("%s: decode error: %v", c.Name(), err)
+			}
+			gotKind, wantKind := "", kind
+			if got.Label != nil {
+				gotKind = got.Label.Kind
+			}
+			if got.Id != e.Id || gotKind != wantKind || len(got.Nodes) != len(e.Nodes) {
+				t.Fatalf("%s: roundtrip mismatch: got %+v, want %+v", c.Name(), got, e)
+			}
+		}
+	})
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ';' {
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	out = append(out, s[start:])
+	return out
+}
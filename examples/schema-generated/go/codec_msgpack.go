@@ -0,0 +1,17 @@
+package schema
+
+import "github.com/vmihailenco/msgpack/v5"
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return "msgpack" }
+
+func (msgpackCodec) MIME() string { return "application/msgpack" }
+
+func (msgpackCodec) Encode(v any) ([]byte, error) { return msgpack.Marshal(v) }
+
+func (msgpackCodec) Decode(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+
+func init() {
+	registerCodec(msgpackCodec{})
+}
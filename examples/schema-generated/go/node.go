@@ -1,16 +1,47 @@
 package schema
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Node struct {
-    Id string `json:"id"`
-    Title string `json:"title"`
-    Position [4]float64 `json:"position"`
+	Id        string         `json:"id"`
+	Title     string         `json:"title"`
+	Position  [4]float64     `json:"position"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+	CreatedAt time.Time      `json:"createdAt,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt,omitempty"`
 }
 
 type HyperEdge struct {
-    Id string `json:"id"`
-    Nodes []string `json:"nodes"`
-    Label string `json:"label,omitempty"`
+	Id    string   `json:"id"`
+	Nodes []string `json:"nodes"`
+	Label *Label   `json:"label,omitempty"`
 }
 
+// Label describes a hyperedge's kind, an optional weight, and arbitrary
+// per-kind attributes. It decodes from either an object, as below, or a
+// bare string, for compatibility with payloads written before Label
+// became structured: a bare string `"label": "friend-of"` decodes into
+// Label{Kind: "friend-of"}.
+type Label struct {
+	Kind   string         `json:"kind"`
+	Weight float64        `json:"weight,omitempty"`
+	Attrs  map[string]any `json:"attrs,omitempty"`
+}
+
+func (l *Label) UnmarshalJSON(data []byte) error {
+	var kind string
+	if err := json.Unmarshal(data, &kind); err == nil {
+		l.Kind = kind
+		return nil
+	}
+	type alias Label
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*l = Label(a)
+	return nil
+}
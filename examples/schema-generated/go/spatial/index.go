@@ -0,0 +1,260 @@
+package spatial
+
+import (
+	"sort"
+	"sync"
+)
+
+// RebuildThreshold is the default fraction of tombstoned entries that
+// triggers a full rebuild of the tree.
+const RebuildThreshold = 0.25
+
+type kdNode struct {
+	id      string
+	point   Point
+	deleted bool
+	left    *kdNode
+	right   *kdNode
+}
+
+// Index is a 4-D k-d tree over node positions, keyed by node id. It
+// supports concurrent readers with a single writer: Nearest, Radius,
+// and Box take an RLock, while Insert (which also moves an existing id),
+// Delete, and Load take a Lock.
+type Index struct {
+	mu sync.RWMutex
+
+	root       *kdNode
+	byID       map[string]*kdNode
+	size       int
+	tombstones int
+
+	// RebuildThreshold overrides the package default when non-zero.
+	RebuildThreshold float64
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{byID: make(map[string]*kdNode)}
+}
+
+func (idx *Index) threshold() float64 {
+	if idx.RebuildThreshold > 0 {
+		return idx.RebuildThreshold
+	}
+	return RebuildThreshold
+}
+
+// Insert adds id at point p, or moves it there if id is already indexed.
+func (idx *Index) Insert(id string, p Point) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if n, ok := idx.byID[id]; ok && !n.deleted {
+		idx.deleteLocked(id)
+	}
+	idx.insertLocked(id, p)
+}
+
+// Delete tombstones id. Once the tombstone ratio crosses the rebuild
+// threshold, the tree is rebuilt from its live entries so deleted nodes
+// stop costing query time.
+func (idx *Index) Delete(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.deleteLocked(id)
+	if idx.size > 0 && float64(idx.tombstones)/float64(idx.size) >= idx.threshold() {
+		idx.rebuildLocked()
+	}
+}
+
+func (idx *Index) insertLocked(id string, p Point) {
+	node := &kdNode{id: id, point: p}
+	idx.byID[id] = node
+	idx.size++
+	if idx.root == nil {
+		idx.root = node
+		return
+	}
+	cur := idx.root
+	depth := 0
+	for {
+		axis := depth % Dims
+		if p[axis] < cur.point[axis] {
+			if cur.left == nil {
+				cur.left = node
+				return
+			}
+			cur = cur.left
+		} else {
+			if cur.right == nil {
+				cur.right = node
+				return
+			}
+			cur = cur.right
+		}
+		depth++
+	}
+}
+
+func (idx *Index) deleteLocked(id string) {
+	n, ok := idx.byID[id]
+	if !ok || n.deleted {
+		return
+	}
+	n.deleted = true
+	idx.tombstones++
+}
+
+// rebuildLocked discards tombstoned entries and rebuilds a balanced tree
+// from what remains, sorting each level on its splitting axis and
+// splitting at the median. Callers must hold idx.mu.
+func (idx *Index) rebuildLocked() {
+	live := make([]*kdNode, 0, idx.size-idx.tombstones)
+	for _, n := range idx.byID {
+		if !n.deleted {
+			live = append(live, &kdNode{id: n.id, point: n.point})
+		}
+	}
+	idx.byID = make(map[string]*kdNode, len(live))
+	for _, n := range live {
+		idx.byID[n.id] = n
+	}
+	idx.root = buildBalanced(live, 0)
+	idx.size = len(live)
+	idx.tombstones = 0
+}
+
+func buildBalanced(nodes []*kdNode, depth int) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	axis := depth % Dims
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].point[axis] < nodes[j].point[axis] })
+	mid := len(nodes) / 2
+	root := nodes[mid]
+	root.left = buildBalanced(nodes[:mid], depth+1)
+	root.right = buildBalanced(nodes[mid+1:], depth+1)
+	return root
+}
+
+type scored struct {
+	id   string
+	dist float64
+}
+
+// Nearest returns up to k ids closest to p, ordered nearest-first. It
+// prunes subtrees whose splitting plane is already farther than the
+// current k-th best distance, so a shallow, balanced tree visits far
+// fewer nodes than a linear scan.
+func (idx *Index) Nearest(p Point, k int) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if k <= 0 || idx.root == nil {
+		return nil
+	}
+	var best []scored // kept sorted ascending by dist, capped at k
+	insert := func(id string, d float64) {
+		i := sort.Search(len(best), func(i int) bool { return best[i].dist >= d })
+		if i == len(best) {
+			if len(best) < k {
+				best = append(best, scored{id, d})
+			}
+			return
+		}
+		best = append(best, scored{})
+		copy(best[i+1:], best[i:])
+		best[i] = scored{id, d}
+		if len(best) > k {
+			best = best[:k]
+		}
+	}
+	var walk func(n *kdNode, depth int)
+	walk = func(n *kdNode, depth int) {
+		if n == nil {
+			return
+		}
+		if !n.deleted {
+			insert(n.id, sqDist(n.point, p))
+		}
+		axis := depth % Dims
+		diff := p[axis] - n.point[axis]
+		near, far := n.left, n.right
+		if diff > 0 {
+			near, far = n.right, n.left
+		}
+		walk(near, depth+1)
+		if len(best) < k || diff*diff <= best[len(best)-1].dist {
+			walk(far, depth+1)
+		}
+	}
+	walk(idx.root, 0)
+	out := make([]string, len(best))
+	for i, s := range best {
+		out[i] = s.id
+	}
+	return out
+}
+
+// Radius returns every id within r of p. Subtrees whose splitting plane
+// lies further than r from p on its axis are pruned.
+func (idx *Index) Radius(p Point, r float64) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	rSq := r * r
+	var out []string
+	var walk func(n *kdNode, depth int)
+	walk = func(n *kdNode, depth int) {
+		if n == nil {
+			return
+		}
+		if !n.deleted && sqDist(n.point, p) <= rSq {
+			out = append(out, n.id)
+		}
+		axis := depth % Dims
+		diff := p[axis] - n.point[axis]
+		near, far := n.left, n.right
+		if diff > 0 {
+			near, far = n.right, n.left
+		}
+		walk(near, depth+1)
+		if diff*diff <= rSq {
+			walk(far, depth+1)
+		}
+	}
+	walk(idx.root, 0)
+	return out
+}
+
+// Box returns every id whose point falls within [min, max] on every
+// axis. Subtrees entirely outside [min, max] on the splitting axis are
+// pruned.
+func (idx *Index) Box(min, max Point) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var out []string
+	var walk func(n *kdNode, depth int)
+	walk = func(n *kdNode, depth int) {
+		if n == nil {
+			return
+		}
+		if !n.deleted && inBox(n.point, min, max) {
+			out = append(out, n.id)
+		}
+		axis := depth % Dims
+		if n.point[axis] >= min[axis] {
+			walk(n.left, depth+1)
+		}
+		if n.point[axis] <= max[axis] {
+			walk(n.right, depth+1)
+		}
+	}
+	walk(idx.root, 0)
+	return out
+}
+
+// Len returns the number of live (non-tombstoned) entries.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.size - idx.tombstones
+}
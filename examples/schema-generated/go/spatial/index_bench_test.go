@@ -0,0 +1,88 @@
+package spatial
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// linearNearest is the naive O(n) baseline the benchmarks below compare
+// the tree against: scan every point and keep the k smallest distances.
+func linearNearest(points map[string]Point, p Point, k int) []string {
+	type cand struct {
+		id   string
+		dist float64
+	}
+	if k <= 0 {
+		return nil
+	}
+	cands := make([]cand, 0, k+1)
+	for id, q := range points {
+		d := sqDist(p, q)
+		if len(cands) == k && d >= cands[k-1].dist {
+			continue
+		}
+		cands = append(cands, cand{id, d})
+		for j := len(cands) - 1; j > 0 && cands[j].dist < cands[j-1].dist; j-- {
+			cands[j], cands[j-1] = cands[j-1], cands[j]
+		}
+		if len(cands) > k {
+			cands = cands[:k]
+		}
+	}
+	out := make([]string, len(cands))
+	for i := range cands {
+		out[i] = cands[i].id
+	}
+	return out
+}
+
+func buildPoints(n int, seed int64) map[string]Point {
+	rng := rand.New(rand.NewSource(seed))
+	points := make(map[string]Point, n)
+	for i := 0; i < n; i++ {
+		id := string(rune('a')) + itoa(i)
+		points[id] = Point{rng.Float64() * 1000, rng.Float64() * 1000, rng.Float64() * 1000, rng.Float64() * 1000}
+	}
+	return points
+}
+
+func itoa(i int) string {
+	if i == 0 {
+		return "0"
+	}
+	var digits []byte
+	for i > 0 {
+		digits = append([]byte{byte('0' + i%10)}, digits...)
+		i /= 10
+	}
+	return string(digits)
+}
+
+func benchmarkTreeNearest(b *testing.B, n int) {
+	points := buildPoints(n, 1)
+	idx := NewIndex()
+	for id, p := range points {
+		idx.Insert(id, p)
+	}
+	query := Point{500, 500, 500, 500}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Nearest(query, 10)
+	}
+}
+
+func benchmarkLinearNearest(b *testing.B, n int) {
+	points := buildPoints(n, 1)
+	query := Point{500, 500, 500, 500}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearNearest(points, query, 10)
+	}
+}
+
+func BenchmarkTreeNearest10k(b *testing.B)    { benchmarkTreeNearest(b, 10_000) }
+func BenchmarkLinearNearest10k(b *testing.B)  { benchmarkLinearNearest(b, 10_000) }
+func BenchmarkTreeNearest100k(b *testing.B)   { benchmarkTreeNearest(b, 100_000) }
+func BenchmarkLinearNearest100k(b *testing.B) { benchmarkLinearNearest(b, 100_000) }
+func BenchmarkTreeNearest1M(b *testing.B)     { benchmarkTreeNearest(b, 1_000_000) }
+func BenchmarkLinearNearest1M(b *testing.B)   { benchmarkLinearNearest(b, 1_000_000) }
@@ -0,0 +1,71 @@
+package spatial
+
+import (
+	"bytes"
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+func TestNearest(t *testing.T) {
+	idx := NewIndex()
+	idx.Insert("origin", Point{0, 0, 0, 0})
+	idx.Insert("close", Point{1, 0, 0, 0})
+	idx.Insert("far", Point{10, 10, 10, 10})
+
+	got := idx.Nearest(Point{0, 0, 0, 0}, 2)
+	if len(got) != 2 || got[0] != "origin" || got[1] != "close" {
+		t.Fatalf("unexpected nearest order: %v", got)
+	}
+}
+
+func TestRadiusAndBox(t *testing.T) {
+	idx := NewIndex()
+	idx.Insert("a", Point{0, 0, 0, 0})
+	idx.Insert("b", Point{5, 0, 0, 0})
+	idx.Insert("c", Point{100, 0, 0, 0})
+
+	if got := idx.Radius(Point{0, 0, 0, 0}, 5.01); len(got) != 2 {
+		t.Fatalf("expected 2 points within radius, got %v", got)
+	}
+	if got := idx.Box(Point{-1, -1, -1, -1}, Point{6, 1, 1, 1}); len(got) != 2 {
+		t.Fatalf("expected 2 points in box, got %v", got)
+	}
+}
+
+func TestDeleteTriggersRebuild(t *testing.T) {
+	idx := NewIndex()
+	idx.RebuildThreshold = 0.5
+	idx.Insert("a", Point{0, 0, 0, 0})
+	idx.Insert("b", Point{1, 0, 0, 0})
+	idx.Delete("a")
+	if idx.tombstones != 0 {
+		t.Fatalf("expected tombstone ratio 0.5 to trigger a rebuild, got %d tombstones", idx.tombstones)
+	}
+	if idx.Len() != 1 {
+		t.Fatalf("expected 1 live entry after rebuild, got %d", idx.Len())
+	}
+}
+
+func TestSnapshotRestoreRoundtrip(t *testing.T) {
+	idx := NewIndex()
+	idx.Insert("a", Point{1, 2, 3, 4})
+	idx.Insert("b", Point{5, 6, 7, 8})
+
+	var buf bytes.Buffer
+	if err := idx.Snapshot(&buf, schema.DefaultCodec); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	restored := NewIndex()
+	if err := restored.Restore(&buf, schema.DefaultCodec); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.Len() != 2 {
+		t.Fatalf("expected 2 restored entries, got %d", restored.Len())
+	}
+	got := restored.Nearest(Point{1, 2, 3, 4}, 1)
+	if len(got) != 1 || got[0] != "a" {
+		t.Fatalf("unexpected nearest after restore: %v", got)
+	}
+}
@@ -0,0 +1,78 @@
+package spatial
+
+import (
+	"fmt"
+	"io"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// entry is the on-disk representation of one indexed point.
+type entry struct {
+	ID    string `json:"id" cbor:"id" msgpack:"id"`
+	Point Point  `json:"point" cbor:"point" msgpack:"point"`
+}
+
+// Snapshot encodes every live entry in idx with codec and writes it to w,
+// so a server restart can Restore the index instead of paying an
+// O(n log n) rebuild by replaying every node insert.
+func (idx *Index) Snapshot(w io.Writer, codec schema.Codec) error {
+	idx.mu.RLock()
+	entries := make([]entry, 0, idx.size-idx.tombstones)
+	for _, n := range idx.byID {
+		if !n.deleted {
+			entries = append(entries, entry{ID: n.id, Point: n.point})
+		}
+	}
+	idx.mu.RUnlock()
+
+	data, err := codec.Encode(entries)
+	if err != nil {
+		return fmt.Errorf("spatial: encode snapshot: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Restore replaces idx's contents with the entries decoded from r using
+// codec, rebuilding a balanced tree from them directly rather than
+// inserting one at a time.
+func (idx *Index) Restore(r io.Reader, codec schema.Codec) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var entries []entry
+	if err := codec.Decode(data, &entries); err != nil {
+		return fmt.Errorf("spatial: decode snapshot: %w", err)
+	}
+
+	points := make(map[string]Point, len(entries))
+	for _, e := range entries {
+		points[e.ID] = e.Point
+	}
+	idx.Load(points)
+	return nil
+}
+
+// Load replaces idx's contents with points, building a balanced tree
+// directly from them rather than inserting one point at a time. This is
+// the bulk-hydration path Restore uses internally; callers that already
+// hold decoded points in memory (e.g. Graph.SetStore replaying a store's
+// nodes) should call Load directly instead of Insert-ing one by one.
+func (idx *Index) Load(points map[string]Point) {
+	nodes := make([]*kdNode, 0, len(points))
+	byID := make(map[string]*kdNode, len(points))
+	for id, p := range points {
+		n := &kdNode{id: id, point: p}
+		nodes = append(nodes, n)
+		byID[id] = n
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.byID = byID
+	idx.root = buildBalanced(nodes, 0)
+	idx.size = len(nodes)
+	idx.tombstones = 0
+}
@@ -0,0 +1,28 @@
+// Package spatial maintains a 4-D spatial index over schema.Node
+// positions and answers nearest-neighbor, radius, and bounding-box
+// queries against it.
+package spatial
+
+// Dims is the dimensionality of every Point, matching schema.Node.Position.
+const Dims = 4
+
+// Point is a 4-D coordinate, identical in shape to schema.Node.Position.
+type Point [Dims]float64
+
+func sqDist(a, b Point) float64 {
+	var sum float64
+	for i := 0; i < Dims; i++ {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+func inBox(p, min, max Point) bool {
+	for i := 0; i < Dims; i++ {
+		if p[i] < min[i] || p[i] > max[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// Memory is an in-memory Store, extracted from the rpc package's
+// original hard-coded behavior so it can be swapped for Persistent.
+type Memory struct {
+	codec schema.Codec
+
+	mu    sync.RWMutex
+	nodes map[string]schema.Node
+	edges map[string]schema.HyperEdge
+}
+
+// NewMemory returns an empty Memory store. codec controls the wire
+// format used by Snapshot and Restore; schema.DefaultCodec if nil.
+func NewMemory(codec schema.Codec) *Memory {
+	if codec == nil {
+		codec = schema.DefaultCodec
+	}
+	return &Memory{
+		codec: codec,
+		nodes: make(map[string]schema.Node),
+		edges: make(map[string]schema.HyperEdge),
+	}
+}
+
+func (m *Memory) PutNode(n schema.Node) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodes[n.Id] = n
+	return nil
+}
+
+func (m *Memory) GetNode(id string) (schema.Node, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n, ok := m.nodes[id]
+	return n, ok, nil
+}
+
+func (m *Memory) DeleteNode(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.nodes, id)
+	return nil
+}
+
+func (m *Memory) IterateNodes(fn func(schema.Node) bool) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, n := range m.nodes {
+		if !fn(n) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *Memory) PutEdge(e schema.HyperEdge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.edges[e.Id] = e
+	return nil
+}
+
+func (m *Memory) GetEdge(id string) (schema.HyperEdge, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.edges[id]
+	return e, ok, nil
+}
+
+func (m *Memory) DeleteEdge(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.edges, id)
+	return nil
+}
+
+func (m *Memory) IterateEdges(fn func(schema.HyperEdge) bool) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, e := range m.edges {
+		if !fn(e) {
+			break
+		}
+	}
+	return nil
+}
+
+// snapshotDoc is the on-disk shape written by Snapshot and read by
+// Restore.
+type snapshotDoc struct {
+	Nodes []schema.Node      `json:"nodes"`
+	Edges []schema.HyperEdge `json:"edges"`
+}
+
+func (m *Memory) Snapshot(w io.Writer) error {
+	m.mu.RLock()
+	doc := snapshotDoc{
+		Nodes: make([]schema.Node, 0, len(m.nodes)),
+		Edges: make([]schema.HyperEdge, 0, len(m.edges)),
+	}
+	for _, n := range m.nodes {
+		doc.Nodes = append(doc.Nodes, n)
+	}
+	for _, e := range m.edges {
+		doc.Edges = append(doc.Edges, e)
+	}
+	m.mu.RUnlock()
+
+	data, err := m.codec.Encode(doc)
+	if err != nil {
+		return fmt.Errorf("store: encode snapshot: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (m *Memory) Restore(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var doc snapshotDoc
+	if err := m.codec.Decode(data, &doc); err != nil {
+		return fmt.Errorf("store: decode snapshot: %w", err)
+	}
+
+	nodes := make(map[string]schema.Node, len(doc.Nodes))
+	for _, n := range doc.Nodes {
+		nodes[n.Id] = n
+	}
+	edges := make(map[string]schema.HyperEdge, len(doc.Edges))
+	for _, e := range doc.Edges {
+		edges[e.Id] = e
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodes = nodes
+	m.edges = edges
+	return nil
+}
+
+func (m *Memory) Close() error { return nil }
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// deleteRecord is the WAL payload for opDeleteNode and opDeleteEdge: just
+// the id of the record being removed.
+type deleteRecord struct {
+	Id string `json:"id"`
+}
+
+// Persistent is a Store backed by an in-memory mirror for reads, a WAL of
+// every mutation for crash recovery, and periodic snapshots (via Snapshot)
+// that let startup replay shrink to "load latest snapshot, replay the WAL
+// written after it" instead of replaying from the beginning of time.
+type Persistent struct {
+	mem        *Memory
+	wal        *wal
+	codec      schema.Codec
+	snapshotAt string
+
+	// mu serializes every WAL append + mem write against Snapshot and
+	// Restore, so Snapshot always observes mem and seq at the same
+	// point: otherwise a PutNode/PutEdge that lands between Snapshot's
+	// mem read and its seq read would get a seq <= the snapshot's, yet
+	// be missing from the snapshot body, and truncateThrough would then
+	// discard its WAL record for good.
+	mu  sync.Mutex
+	seq uint64
+}
+
+// OpenPersistent opens (or creates) a WAL at walPath and, if a snapshot
+// exists at snapshotPath, loads it before replaying any WAL records
+// written after it. codec controls the wire format for both the WAL
+// payloads and snapshots; schema.DefaultCodec if nil.
+func OpenPersistent(walPath, snapshotPath string, codec schema.Codec) (*Persistent, error) {
+	if codec == nil {
+		codec = schema.DefaultCodec
+	}
+	mem := NewMemory(codec)
+
+	var snapSeq uint64
+	if data, err := os.ReadFile(snapshotPath); err == nil {
+		var doc snapshotEnvelope
+		if err := codec.Decode(data, &doc); err != nil {
+			return nil, fmt.Errorf("store: load snapshot: %w", err)
+		}
+		if err := mem.Restore(bytes.NewReader(doc.Body)); err != nil {
+			return nil, fmt.Errorf("store: restore snapshot: %w", err)
+		}
+		snapSeq = doc.Seq
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("store: open snapshot: %w", err)
+	}
+
+	w, err := openWAL(walPath, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Persistent{mem: mem, wal: w, codec: codec, snapshotAt: snapshotPath, seq: snapSeq}
+	if err := w.replay(snapSeq, p.apply); err != nil {
+		w.close()
+		return nil, fmt.Errorf("store: replay wal: %w", err)
+	}
+	return p, nil
+}
+
+func (p *Persistent) apply(rec record) error {
+	p.seq = rec.Seq
+	switch rec.Op {
+	case opPutNode:
+		var n schema.Node
+		if err := p.codec.Decode(rec.Payload, &n); err != nil {
+			return err
+		}
+		return p.mem.PutNode(n)
+	case opDeleteNode:
+		var d deleteRecord
+		if err := p.codec.Decode(rec.Payload, &d); err != nil {
+			return err
+		}
+		return p.mem.DeleteNode(d.Id)
+	case opPutEdge:
+		var e schema.HyperEdge
+		if err := p.codec.Decode(rec.Payload, &e); err != nil {
+			return err
+		}
+		return p.mem.PutEdge(e)
+	case opDeleteEdge:
+		var d deleteRecord
+		if err := p.codec.Decode(rec.Payload, &d); err != nil {
+			return err
+		}
+		return p.mem.DeleteEdge(d.Id)
+	default:
+		return fmt.Errorf("store: unknown wal op %d at seq %d", rec.Op, rec.Seq)
+	}
+}
+
+// mutateLocked appends v as a WAL record of kind o, applies apply (a
+// p.mem write) on success, and advances p.seq, all under mu so a
+// concurrent Snapshot can never observe mem and seq from different
+// points in this mutation.
+func (p *Persistent) mutateLocked(o op, v any, apply func() error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	seq, err := p.wal.append(p.seq, o, v)
+	if err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	p.seq = seq
+	return nil
+}
+
+func (p *Persistent) PutNode(n schema.Node) error {
+	return p.mutateLocked(opPutNode, n, func() error { return p.mem.PutNode(n) })
+}
+
+func (p *Persistent) GetNode(id string) (schema.Node, bool, error) { return p.mem.GetNode(id) }
+
+func (p *Persistent) DeleteNode(id string) error {
+	return p.mutateLocked(opDeleteNode, deleteRecord{Id: id}, func() error { return p.mem.DeleteNode(id) })
+}
+
+func (p *Persistent) IterateNodes(fn func(schema.Node) bool) error { return p.mem.IterateNodes(fn) }
+
+func (p *Persistent) PutEdge(e schema.HyperEdge) error {
+	return p.mutateLocked(opPutEdge, e, func() error { return p.mem.PutEdge(e) })
+}
+
+func (p *Persistent) GetEdge(id string) (schema.HyperEdge, bool, error) { return p.mem.GetEdge(id) }
+
+func (p *Persistent) DeleteEdge(id string) error {
+	return p.mutateLocked(opDeleteEdge, deleteRecord{Id: id}, func() error { return p.mem.DeleteEdge(id) })
+}
+
+func (p *Persistent) IterateEdges(fn func(schema.HyperEdge) bool) error { return p.mem.IterateEdges(fn) }
+
+// snapshotEnvelope wraps a Memory snapshot with the WAL sequence number
+// it was taken at, so a later OpenPersistent knows how far to skip ahead
+// when replaying.
+type snapshotEnvelope struct {
+	Seq  uint64 `json:"seq"`
+	Body []byte `json:"body"`
+}
+
+// Snapshot writes the current state plus its WAL sequence number to
+// p.snapshotAt, then truncates the WAL up through that sequence number,
+// since it is now redundant with the snapshot. w also receives the same
+// encoded bytes, so callers (such as the admin.snapshot RPC method) can
+// return them to the caller without a second read of the snapshot file.
+func (p *Persistent) Snapshot(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := p.mem.Snapshot(&buf); err != nil {
+		return err
+	}
+	env := snapshotEnvelope{Seq: p.seq, Body: buf.Bytes()}
+
+	data, err := p.codec.Encode(env)
+	if err != nil {
+		return fmt.Errorf("store: encode snapshot envelope: %w", err)
+	}
+	if err := os.WriteFile(p.snapshotAt, data, 0600); err != nil {
+		return fmt.Errorf("store: write snapshot file: %w", err)
+	}
+	if w != nil {
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return p.wal.truncateThrough(env.Seq)
+}
+
+// Restore replaces the store's contents with a prior Snapshot and resets
+// replay position to that snapshot's sequence number. Any WAL records
+// written since are discarded, matching the semantics of a point-in-time
+// restore.
+func (p *Persistent) Restore(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var env snapshotEnvelope
+	if err := p.codec.Decode(data, &env); err != nil {
+		return fmt.Errorf("store: decode snapshot envelope: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.mem.Restore(bytes.NewReader(env.Body)); err != nil {
+		return err
+	}
+	if err := p.wal.truncateThrough(p.seq); err != nil {
+		return err
+	}
+	p.seq = env.Seq
+	return nil
+}
+
+func (p *Persistent) Close() error {
+	return p.wal.close()
+}
@@ -0,0 +1,34 @@
+// Package store defines the persistence interface the rpc package's
+// Graph writes through to, plus a plain in-memory implementation and a
+// WAL-backed implementation that survives a restart.
+package store
+
+import (
+	"io"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// Store persists schema.Node and schema.HyperEdge values. Implementations
+// must be safe for concurrent use.
+type Store interface {
+	PutNode(n schema.Node) error
+	GetNode(id string) (schema.Node, bool, error)
+	DeleteNode(id string) error
+	IterateNodes(fn func(schema.Node) bool) error
+
+	PutEdge(e schema.HyperEdge) error
+	GetEdge(id string) (schema.HyperEdge, bool, error)
+	DeleteEdge(id string) error
+	IterateEdges(fn func(schema.HyperEdge) bool) error
+
+	// Snapshot writes every node and edge currently in the store to w,
+	// in a codec-encoded form suitable for Restore.
+	Snapshot(w io.Writer) error
+	// Restore replaces the store's contents with a prior Snapshot.
+	Restore(r io.Reader) error
+
+	// Close releases any resources (open files, background goroutines)
+	// held by the store.
+	Close() error
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+func TestMemorySnapshotRestore(t *testing.T) {
+	m := NewMemory(nil)
+	if err := m.PutNode(schema.Node{Id: "a", Title: "Alice"}); err != nil {
+		t.Fatalf("PutNode: %v", err)
+	}
+	if err := m.PutEdge(schema.HyperEdge{Id: "e1", Nodes: []string{"a"}}); err != nil {
+		t.Fatalf("PutEdge: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	restored := NewMemory(nil)
+	if err := restored.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	n, ok, err := restored.GetNode("a")
+	if err != nil || !ok || n.Title != "Alice" {
+		t.Fatalf("GetNode after restore: n=%+v ok=%v err=%v", n, ok, err)
+	}
+	if _, ok, _ := restored.GetEdge("e1"); !ok {
+		t.Fatalf("expected edge e1 to survive restore")
+	}
+}
+
+func TestPersistentReplaysWALAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	walPath := filepath.Join(dir, "wal.db")
+	snapPath := filepath.Join(dir, "snapshot")
+
+	p, err := OpenPersistent(walPath, snapPath, nil)
+	if err != nil {
+		t.Fatalf("OpenPersistent: %v", err)
+	}
+	if err := p.PutNode(schema.Node{Id: "a", Title: "Alice"}); err != nil {
+		t.Fatalf("PutNode: %v", err)
+	}
+	if err := p.PutNode(schema.Node{Id: "b", Title: "Bob"}); err != nil {
+		t.Fatalf("PutNode: %v", err)
+	}
+	if err := p.DeleteNode("b"); err != nil {
+		t.Fatalf("DeleteNode: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := OpenPersistent(walPath, snapPath, nil)
+	if err != nil {
+		t.Fatalf("reopen OpenPersistent: %v", err)
+	}
+	defer reopened.Close()
+
+	n, ok, err := reopened.GetNode("a")
+	if err != nil || !ok || n.Title != "Alice" {
+		t.Fatalf("GetNode(a) after replay: n=%+v ok=%v err=%v", n, ok, err)
+	}
+	if _, ok, _ := reopened.GetNode("b"); ok {
+		t.Fatalf("expected node b to stay deleted after replay")
+	}
+}
+
+func TestPersistentSnapshotTruncatesWAL(t *testing.T) {
+	dir := t.TempDir()
+	walPath := filepath.Join(dir, "wal.db")
+	snapPath := filepath.Join(dir, "snapshot")
+
+	p, err := OpenPersistent(walPath, snapPath, nil)
+	if err != nil {
+		t.Fatalf("OpenPersistent: %v", err)
+	}
+	if err := p.PutNode(schema.Node{Id: "a", Title: "Alice"}); err != nil {
+		t.Fatalf("PutNode: %v", err)
+	}
+	if err := p.Snapshot(nil); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	var remaining int
+	if err := p.wal.replay(0, func(record) error { remaining++; return nil }); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("expected Snapshot to truncate the WAL, %d records remain", remaining)
+	}
+	if err := p.PutNode(schema.Node{Id: "c", Title: "Carol"}); err != nil {
+		t.Fatalf("PutNode: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := OpenPersistent(walPath, snapPath, nil)
+	if err != nil {
+		t.Fatalf("reopen OpenPersistent: %v", err)
+	}
+	defer reopened.Close()
+
+	if _, ok, _ := reopened.GetNode("a"); !ok {
+		t.Fatalf("expected node a to survive snapshot+reopen")
+	}
+	if _, ok, _ := reopened.GetNode("c"); !ok {
+		t.Fatalf("expected node c (written after snapshot) to survive replay")
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// op identifies the kind of mutation a wal record carries.
+type op byte
+
+const (
+	opPutNode op = iota + 1
+	opDeleteNode
+	opPutEdge
+	opDeleteEdge
+)
+
+var walBucket = []byte("wal")
+
+// record is one WAL entry: an operation plus its codec-encoded payload
+// (a schema.Node for opPutNode, a schema.HyperEdge for opPutEdge, or a
+// deleteRecord holding the id for the two delete ops).
+type record struct {
+	Seq     uint64
+	Op      op
+	Payload []byte
+}
+
+// wal is an append-only log of mutations backed by a bbolt database,
+// keyed by a monotonically increasing sequence number so entries replay
+// in the order they were written.
+type wal struct {
+	db    *bolt.DB
+	codec schema.Codec
+}
+
+func openWAL(path string, codec schema.Codec) (*wal, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store: open wal: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(walBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: init wal bucket: %w", err)
+	}
+	return &wal{db: db, codec: codec}, nil
+}
+
+func seqKey(seq uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	return k
+}
+
+// append writes a new record after seq (the last sequence number known
+// to the caller) and returns the sequence number it was assigned.
+func (w *wal) append(seq uint64, o op, payload any) (uint64, error) {
+	data, err := w.codec.Encode(payload)
+	if err != nil {
+		return 0, fmt.Errorf("store: encode wal payload: %w", err)
+	}
+	next := seq + 1
+	err = w.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(walBucket)
+		return b.Put(seqKey(next), append([]byte{byte(o)}, data...))
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store: append wal: %w", err)
+	}
+	return next, nil
+}
+
+// replay invokes fn for every record with a sequence number greater than
+// after, in order.
+func (w *wal) replay(after uint64, fn func(record) error) error {
+	return w.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(walBucket).Cursor()
+		for k, v := c.Seek(seqKey(after + 1)); k != nil; k, v = c.Next() {
+			seq := binary.BigEndian.Uint64(k)
+			rec := record{Seq: seq, Op: op(v[0]), Payload: v[1:]}
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// truncateThrough deletes every record with a sequence number <= seq,
+// called after a snapshot makes them redundant.
+func (w *wal) truncateThrough(seq uint64) error {
+	return w.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(walBucket).Cursor()
+		for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) <= seq; k, _ = c.Next() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (w *wal) close() error {
+	return w.db.Close()
+}
@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Validator checks a HyperEdge against a user-supplied JSON Schema
+// (draft 2020-12) describing the allowed Label.Kind values, the Attrs
+// keys required for each kind, and min/max cardinality on Nodes per
+// kind. Express per-kind rules with an "if/then" schema keyed on
+// "kind", e.g.:
+//
+//	{
+//	  "$schema": "https://json-schema.org/draft/2020-12/schema",
+//	  "type": "object",
+//	  "properties": {"kind": {"type": "string"}, "nodes": {"type": "array"}},
+//	  "allOf": [
+//	    {"if": {"properties": {"kind": {"const": "friend-of"}}},
+//	     "then": {"properties": {"nodes": {"minItems": 2, "maxItems": 2}},
+//	              "required": ["attrs"]}}
+//	  ]
+//	}
+type Validator struct {
+	schema *jsonschema.Schema
+}
+
+// NewValidator compiles a draft 2020-12 JSON Schema document read from r.
+func NewValidator(r io.Reader) (*Validator, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	if err := compiler.AddResource("hyperedge.json", r); err != nil {
+		return nil, fmt.Errorf("schema: add resource: %w", err)
+	}
+	sch, err := compiler.Compile("hyperedge.json")
+	if err != nil {
+		return nil, fmt.Errorf("schema: compile: %w", err)
+	}
+	return &Validator{schema: sch}, nil
+}
+
+// ValidationError reports every JSON Pointer within a HyperEdge document
+// that failed validation.
+type ValidationError struct {
+	Pointers []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("schema: validation failed at %v", e.Pointers)
+}
+
+// ValidateEdge validates e against v's schema, returning a
+// *ValidationError listing every violating JSON Pointer when e does not
+// conform.
+func (v *Validator) ValidateEdge(e HyperEdge) error {
+	nodes := make([]any, len(e.Nodes))
+	for i, id := range e.Nodes {
+		nodes[i] = id
+	}
+	doc := map[string]any{"nodes": nodes}
+	if e.Label != nil {
+		doc["kind"] = e.Label.Kind
+		doc["weight"] = e.Label.Weight
+		if e.Label.Attrs != nil {
+			doc["attrs"] = e.Label.Attrs
+		}
+	}
+	if err := v.schema.Validate(doc); err != nil {
+		if ve, ok := err.(*jsonschema.ValidationError); ok {
+			return &ValidationError{Pointers: collectPointers(ve)}
+		}
+		return err
+	}
+	return nil
+}
+
+// collectPointers flattens a jsonschema validation error tree into the
+// JSON Pointer of every leaf failure.
+func collectPointers(ve *jsonschema.ValidationError) []string {
+	if len(ve.Causes) == 0 {
+		return []string{ve.InstanceLocation}
+	}
+	var out []string
+	for _, cause := range ve.Causes {
+		out = append(out, collectPointers(cause)...)
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+const testEdgeSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "allOf": [
+    {
+      "if": {"properties": {"kind": {"const": "friend-of"}}},
+      "then": {
+        "required": ["attrs"],
+        "properties": {
+          "nodes": {"minItems": 2, "maxItems": 2},
+          "attrs": {"required": ["since"]}
+        }
+      }
+    }
+  ]
+}`
+
+func TestValidatorRejectsMissingAttrs(t *testing.T) {
+	v, err := NewValidator(strings.NewReader(testEdgeSchema))
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+	e := HyperEdge{Id: "e1", Nodes: []string{"a", "b"}, Label: &Label{Kind: "friend-of"}}
+	if err := v.ValidateEdge(e); err == nil {
+		t.Fatal("expected validation to fail for a friend-of edge missing attrs.since")
+	}
+}
+
+func TestValidatorAcceptsValidEdge(t *testing.T) {
+	v, err := NewValidator(strings.NewReader(testEdgeSchema))
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+	e := HyperEdge{
+		Id:    "e1",
+		Nodes: []string{"a", "b"},
+		Label: &Label{Kind: "friend-of", Attrs: map[string]any{"since": "2020-01-01"}},
+	}
+	if err := v.ValidateEdge(e); err != nil {
+		t.Fatalf("expected a valid edge to pass, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"mime"
+	"net/http"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// negotiateHTTPCodec picks the one wire codec that both decodes an HTTP
+// request's body and encodes its response: the codec named by the
+// Content-Type header, falling back to the Accept header (matched as a
+// single exact MIME type) and finally DefaultCodec when Content-Type is
+// absent or unrecognized.
+func negotiateHTTPCodec(r *http.Request) schema.Codec {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if mediaType, _, err := mime.ParseMediaType(ct); err == nil {
+			if c := schema.CodecByMIME(mediaType); c != nil {
+				return c
+			}
+		}
+	}
+	if accept := r.Header.Get("Accept"); accept != "" {
+		if c := schema.CodecByMIME(accept); c != nil {
+			return c
+		}
+	}
+	return schema.DefaultCodec
+}
+
+// wsSubprotocols lists every registered codec name, in the order the
+// server advertises them to the WebSocket upgrader.
+func wsSubprotocols() []string {
+	names := make([]string, 0, 3)
+	for _, n := range []string{"json", "msgpack", "cbor"} {
+		if schema.CodecByName(n) != nil {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// transcode re-encodes data from one codec's wire format to another by
+// decoding into a generic value and re-encoding it.
+func transcode(data []byte, from, to schema.Codec) ([]byte, error) {
+	if from.Name() == to.Name() {
+		return data, nil
+	}
+	var v any
+	if err := from.Decode(data, &v); err != nil {
+		return nil, err
+	}
+	return to.Encode(v)
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+func TestNegotiateHTTPCodec(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Content-Type", "application/msgpack")
+	if c := negotiateHTTPCodec(req); c.Name() != "msgpack" {
+		t.Fatalf("expected msgpack, got %s", c.Name())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	if c := negotiateHTTPCodec(req); c.Name() != schema.DefaultCodec.Name() {
+		t.Fatalf("expected default codec without a Content-Type, got %s", c.Name())
+	}
+}
+
+func TestDispatchOverMsgpack(t *testing.T) {
+	s := NewServer()
+	codec := schema.CodecByName("msgpack")
+	addReq, err := codec.Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "graph.addNode",
+		"params":  map[string]any{"id": "n1", "title": "Alice", "position": []float64{0, 0, 0, 0}},
+		"id":      1,
+	})
+	if err != nil {
+		t.Fatalf("encode request: %v", err)
+	}
+	out := s.Dispatch(addReq, codec, nil)
+	var resp map[string]any
+	if err := codec.Decode(out, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["error"] != nil {
+		t.Fatalf("unexpected error: %v", resp["error"])
+	}
+}
+
+func TestDispatchOverCBOR(t *testing.T) {
+	s := NewServer()
+	codec := schema.CodecByName("cbor")
+	addReq, err := codec.Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "graph.addNode",
+		"params":  map[string]any{"id": "n1", "title": "Alice", "position": []float64{0, 0, 0, 0}},
+		"id":      1,
+	})
+	if err != nil {
+		t.Fatalf("encode request: %v", err)
+	}
+	out := s.Dispatch(addReq, codec, nil)
+	var resp map[string]any
+	if err := codec.Decode(out, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["error"] != nil {
+		t.Fatalf("unexpected error: %v", resp["error"])
+	}
+}
@@ -0,0 +1,26 @@
+package rpc
+
+import schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+
+// Event kinds, carried by the Events Graph.Watch delivers and checked by
+// Subscription.matches.
+const (
+	EventKindNode = "node"
+	EventKindEdge = "edge"
+)
+
+// Event actions.
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// Event describes a single mutation to the Graph. Exactly one of Node or
+// Edge is set, matching Kind.
+type Event struct {
+	Kind   string
+	Action string
+	Node   *schema.Node
+	Edge   *schema.HyperEdge
+}
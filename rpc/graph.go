@@ -0,0 +1,381 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+	"github.com/Imnsol/rRPC/examples/schema-generated/go/spatial"
+	"github.com/Imnsol/rRPC/examples/schema-generated/go/store"
+)
+
+// Graph is an in-memory, concurrency-safe store of schema.Node and
+// schema.HyperEdge values, keyed by their Id. It backs the graph.*
+// RPC methods.
+type Graph struct {
+	mu    sync.RWMutex
+	nodes map[string]schema.Node
+	edges map[string]schema.HyperEdge
+
+	watchersMu sync.Mutex
+	watchers   []chan Event
+
+	validator *schema.Validator
+	index     *spatial.Index
+	persist   store.Store
+}
+
+// NewGraph returns an empty Graph.
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]schema.Node),
+		edges: make(map[string]schema.HyperEdge),
+		index: spatial.NewIndex(),
+	}
+}
+
+// SetValidator installs v to run against every HyperEdge before it is
+// persisted by AddEdge. A nil validator disables validation, which is
+// also the default.
+func (g *Graph) SetValidator(v *schema.Validator) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.validator = v
+}
+
+// SetStore installs s as the Graph's write-through persistence backend
+// and replaces the Graph's in-memory state with whatever s already
+// contains (the usual case being a store.Persistent that has just
+// replayed its snapshot and WAL). A nil store disables persistence,
+// which is also the default. SetStore is safe to call again later, e.g.
+// to rehydrate after an admin.restore.
+func (g *Graph) SetStore(s store.Store) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.persist = s
+	g.nodes = make(map[string]schema.Node)
+	g.edges = make(map[string]schema.HyperEdge)
+	g.index = spatial.NewIndex()
+	if s == nil {
+		return nil
+	}
+	points := make(map[string]spatial.Point)
+	if err := s.IterateNodes(func(n schema.Node) bool {
+		g.nodes[n.Id] = n
+		points[n.Id] = spatial.Point(n.Position)
+		return true
+	}); err != nil {
+		return fmt.Errorf("graph: hydrate nodes: %w", err)
+	}
+	g.index.Load(points)
+	if err := s.IterateEdges(func(e schema.HyperEdge) bool {
+		g.edges[e.Id] = e
+		return true
+	}); err != nil {
+		return fmt.Errorf("graph: hydrate edges: %w", err)
+	}
+	return nil
+}
+
+// Watch registers a new event channel that receives every subsequent
+// node and edge mutation. The channel is buffered to buf and events are
+// dropped, never blocking the mutation that produced them, if the
+// subscriber falls behind; callers that need reliable delivery (the
+// subscription registry) should size buf generously and drain promptly.
+// The returned cancel func unregisters and closes the channel.
+func (g *Graph) Watch(buf int) (<-chan Event, func()) {
+	ch := make(chan Event, buf)
+	g.watchersMu.Lock()
+	g.watchers = append(g.watchers, ch)
+	g.watchersMu.Unlock()
+
+	cancel := func() {
+		g.watchersMu.Lock()
+		defer g.watchersMu.Unlock()
+		for i, w := range g.watchers {
+			if w == ch {
+				g.watchers = append(g.watchers[:i], g.watchers[i+1:]...)
+				close(ch)
+				return
+			}
+		}
+	}
+	return ch, cancel
+}
+
+func (g *Graph) broadcast(ev Event) {
+	g.watchersMu.Lock()
+	defer g.watchersMu.Unlock()
+	for _, ch := range g.watchers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// AddNode inserts n, failing if a node with the same Id already exists.
+// CreatedAt and UpdatedAt are stamped with the current time, overriding
+// whatever the caller supplied.
+func (g *Graph) AddNode(n schema.Node) error {
+	now := time.Now()
+	n.CreatedAt = now
+	n.UpdatedAt = now
+	g.mu.Lock()
+	if _, ok := g.nodes[n.Id]; ok {
+		g.mu.Unlock()
+		return fmt.Errorf("node %q already exists", n.Id)
+	}
+	if g.persist != nil {
+		if err := g.persist.PutNode(n); err != nil {
+			g.mu.Unlock()
+			return fmt.Errorf("node %q: %w", n.Id, err)
+		}
+	}
+	g.nodes[n.Id] = n
+	g.mu.Unlock()
+	g.index.Insert(n.Id, spatial.Point(n.Position))
+	g.broadcast(Event{Kind: EventKindNode, Action: EventCreated, Node: &n})
+	return nil
+}
+
+// UpdateNode replaces an existing node, failing if it is not present.
+// CreatedAt is preserved from the existing record; UpdatedAt is stamped
+// with the current time.
+func (g *Graph) UpdateNode(n schema.Node) error {
+	g.mu.Lock()
+	existing, ok := g.nodes[n.Id]
+	if !ok {
+		g.mu.Unlock()
+		return fmt.Errorf("node %q not found", n.Id)
+	}
+	n.CreatedAt = existing.CreatedAt
+	n.UpdatedAt = time.Now()
+	if g.persist != nil {
+		if err := g.persist.PutNode(n); err != nil {
+			g.mu.Unlock()
+			return fmt.Errorf("node %q: %w", n.Id, err)
+		}
+	}
+	g.nodes[n.Id] = n
+	g.mu.Unlock()
+	g.index.Insert(n.Id, spatial.Point(n.Position))
+	g.broadcast(Event{Kind: EventKindNode, Action: EventUpdated, Node: &n})
+	return nil
+}
+
+// GetNode looks up a node by id.
+func (g *Graph) GetNode(id string) (schema.Node, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	n, ok := g.nodes[id]
+	return n, ok
+}
+
+// DeleteNode removes a node by id. It also strips the node from any
+// hyperedge that references it.
+func (g *Graph) DeleteNode(id string) error {
+	g.mu.Lock()
+	n, ok := g.nodes[id]
+	if !ok {
+		g.mu.Unlock()
+		return fmt.Errorf("node %q not found", id)
+	}
+	if g.persist != nil {
+		if err := g.persist.DeleteNode(id); err != nil {
+			g.mu.Unlock()
+			return fmt.Errorf("node %q: %w", id, err)
+		}
+	}
+	delete(g.nodes, id)
+	var touched []schema.HyperEdge
+	for eid, e := range g.edges {
+		kept := e.Nodes[:0:0]
+		for _, nid := range e.Nodes {
+			if nid != id {
+				kept = append(kept, nid)
+			}
+		}
+		if len(kept) == len(e.Nodes) {
+			continue
+		}
+		e.Nodes = kept
+		if g.persist != nil {
+			if err := g.persist.PutEdge(e); err != nil {
+				g.mu.Unlock()
+				return fmt.Errorf("edge %q: %w", eid, err)
+			}
+		}
+		g.edges[eid] = e
+		touched = append(touched, e)
+	}
+	g.mu.Unlock()
+
+	g.index.Delete(id)
+	g.broadcast(Event{Kind: EventKindNode, Action: EventDeleted, Node: &n})
+	for _, e := range touched {
+		e := e
+		g.broadcast(Event{Kind: EventKindEdge, Action: EventUpdated, Edge: &e})
+	}
+	return nil
+}
+
+// AddEdge inserts e, failing if an edge with the same Id already exists,
+// if it references an unknown node, or if a validator is installed and
+// rejects e (in which case the error is a *schema.ValidationError).
+func (g *Graph) AddEdge(e schema.HyperEdge) error {
+	g.mu.Lock()
+	if _, ok := g.edges[e.Id]; ok {
+		g.mu.Unlock()
+		return fmt.Errorf("edge %q already exists", e.Id)
+	}
+	for _, nid := range e.Nodes {
+		if _, ok := g.nodes[nid]; !ok {
+			g.mu.Unlock()
+			return fmt.Errorf("edge %q references unknown node %q", e.Id, nid)
+		}
+	}
+	validator := g.validator
+	if validator != nil {
+		if err := validator.ValidateEdge(e); err != nil {
+			g.mu.Unlock()
+			return err
+		}
+	}
+	if g.persist != nil {
+		if err := g.persist.PutEdge(e); err != nil {
+			g.mu.Unlock()
+			return fmt.Errorf("edge %q: %w", e.Id, err)
+		}
+	}
+	g.edges[e.Id] = e
+	g.mu.Unlock()
+	g.broadcast(Event{Kind: EventKindEdge, Action: EventCreated, Edge: &e})
+	return nil
+}
+
+// GetEdge looks up a hyperedge by id.
+func (g *Graph) GetEdge(id string) (schema.HyperEdge, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	e, ok := g.edges[id]
+	return e, ok
+}
+
+// Neighbors returns the ids of every node that shares a hyperedge with id.
+func (g *Graph) Neighbors(id string) []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	seen := map[string]bool{}
+	var out []string
+	for _, e := range g.edges {
+		member := false
+		for _, nid := range e.Nodes {
+			if nid == id {
+				member = true
+				break
+			}
+		}
+		if !member {
+			continue
+		}
+		for _, nid := range e.Nodes {
+			if nid != id && !seen[nid] {
+				seen[nid] = true
+				out = append(out, nid)
+			}
+		}
+	}
+	return out
+}
+
+// Neighborhood expands seeds outward by up to hops hyperedge hops (via
+// repeated Neighbors calls), returning seeds and every node reached
+// along the way. It backs graph.subscribe's "neighborhood" kind, which
+// watches a fixed set of nodes rather than re-walking the graph on every
+// event.
+func (g *Graph) Neighborhood(seeds []string, hops int) map[string]bool {
+	set := make(map[string]bool, len(seeds))
+	frontier := make([]string, 0, len(seeds))
+	for _, id := range seeds {
+		if !set[id] {
+			set[id] = true
+			frontier = append(frontier, id)
+		}
+	}
+	for h := 0; h < hops && len(frontier) > 0; h++ {
+		var next []string
+		for _, id := range frontier {
+			for _, nb := range g.Neighbors(id) {
+				if !set[nb] {
+					set[nb] = true
+					next = append(next, nb)
+				}
+			}
+		}
+		frontier = next
+	}
+	return set
+}
+
+// QueryFilter narrows Graph.Query to nodes whose Title contains Title,
+// ignoring case (when non-empty), and edges whose Label.Kind equals Label
+// (when non-empty).
+type QueryFilter struct {
+	Title string `json:"title,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+// QueryResult is the combined match set returned by Graph.Query.
+type QueryResult struct {
+	Nodes []schema.Node      `json:"nodes"`
+	Edges []schema.HyperEdge `json:"edges"`
+}
+
+// Query returns every node and edge matching f. An empty filter matches
+// everything.
+func (g *Graph) Query(f QueryFilter) QueryResult {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var res QueryResult
+	for _, n := range g.nodes {
+		if f.Title == "" || strings.Contains(strings.ToLower(n.Title), strings.ToLower(f.Title)) {
+			res.Nodes = append(res.Nodes, n)
+		}
+	}
+	for _, e := range g.edges {
+		if f.Label == "" || (e.Label != nil && e.Label.Kind == f.Label) {
+			res.Edges = append(res.Edges, e)
+		}
+	}
+	return res
+}
+
+// Nearest returns up to k nodes closest to p, ordered nearest-first.
+func (g *Graph) Nearest(p spatial.Point, k int) []schema.Node {
+	return g.resolveNodes(g.index.Nearest(p, k))
+}
+
+// Radius returns every node within r of p.
+func (g *Graph) Radius(p spatial.Point, r float64) []schema.Node {
+	return g.resolveNodes(g.index.Radius(p, r))
+}
+
+// Box returns every node whose Position falls within [min, max].
+func (g *Graph) Box(min, max spatial.Point) []schema.Node {
+	return g.resolveNodes(g.index.Box(min, max))
+}
+
+func (g *Graph) resolveNodes(ids []string) []schema.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]schema.Node, 0, len(ids))
+	for _, id := range ids {
+		if n, ok := g.nodes[id]; ok {
+			out = append(out, n)
+		}
+	}
+	return out
+}
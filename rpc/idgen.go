@@ -0,0 +1,14 @@
+package rpc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newSubscriptionID returns a random hex identifier for a new
+// Subscription, in the same spirit as go-ethereum's rpc.NewID.
+func newSubscriptionID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
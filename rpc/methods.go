@@ -0,0 +1,267 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+	"github.com/Imnsol/rRPC/examples/schema-generated/go/spatial"
+)
+
+// handlerFunc executes one already-decoded method call and returns either
+// a JSON-marshalable result or an *Error to surface to the caller. cs is
+// nil for requests arriving over a non-persistent transport (plain HTTP
+// POST); only subscribe/unsubscribe require it.
+type handlerFunc func(g *Graph, cs *connState, params json.RawMessage) (any, *Error)
+
+// methods is the graph.* and admin.* method table. Method names follow the
+// namespace.verb convention used throughout the spec (e.g. eth_call).
+var methods = map[string]handlerFunc{
+	"graph.addNode":     handleAddNode,
+	"graph.updateNode":  handleUpdateNode,
+	"graph.getNode":     handleGetNode,
+	"graph.deleteNode":  handleDeleteNode,
+	"graph.addEdge":     handleAddEdge,
+	"graph.getEdge":     handleGetEdge,
+	"graph.neighbors":   handleNeighbors,
+	"graph.query":       handleQuery,
+	"graph.subscribe":   handleSubscribe,
+	"graph.unsubscribe": handleUnsubscribe,
+	"graph.nearest":     handleNearest,
+	"graph.radius":      handleRadius,
+	"graph.box":         handleBox,
+	"admin.snapshot":    handleAdminSnapshot,
+	"admin.restore":     handleAdminRestore,
+}
+
+func decodeParams(params json.RawMessage, v any) *Error {
+	if len(params) == 0 {
+		return newError(ErrCodeInvalidParams, "missing params")
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return newError(ErrCodeInvalidParams, err.Error())
+	}
+	return nil
+}
+
+func handleAddNode(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var n schema.Node
+	if err := decodeParams(params, &n); err != nil {
+		return nil, err
+	}
+	if err := g.AddNode(n); err != nil {
+		return nil, newError(ErrCodeInvalidParams, err.Error())
+	}
+	return n, nil
+}
+
+func handleUpdateNode(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var n schema.Node
+	if err := decodeParams(params, &n); err != nil {
+		return nil, err
+	}
+	if err := g.UpdateNode(n); err != nil {
+		return nil, newError(ErrCodeNotFound, err.Error())
+	}
+	return n, nil
+}
+
+func handleGetNode(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Id string `json:"id"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	n, ok := g.GetNode(p.Id)
+	if !ok {
+		return nil, newError(ErrCodeNotFound, "node not found")
+	}
+	return n, nil
+}
+
+func handleDeleteNode(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Id string `json:"id"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := g.DeleteNode(p.Id); err != nil {
+		return nil, newError(ErrCodeNotFound, err.Error())
+	}
+	return true, nil
+}
+
+func handleAddEdge(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var e schema.HyperEdge
+	if err := decodeParams(params, &e); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(e); err != nil {
+		if ve, ok := err.(*schema.ValidationError); ok {
+			return nil, newErrorData(ErrCodeValidation, "edge failed schema validation", ve.Pointers)
+		}
+		return nil, newError(ErrCodeInvalidParams, err.Error())
+	}
+	return e, nil
+}
+
+func handleGetEdge(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Id string `json:"id"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	e, ok := g.GetEdge(p.Id)
+	if !ok {
+		return nil, newError(ErrCodeNotFound, "edge not found")
+	}
+	return e, nil
+}
+
+func handleNeighbors(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Id string `json:"id"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	return g.Neighbors(p.Id), nil
+}
+
+func handleQuery(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var f QueryFilter
+	if len(params) > 0 {
+		if err := decodeParams(params, &f); err != nil {
+			return nil, err
+		}
+	}
+	return g.Query(f), nil
+}
+
+func handleSubscribe(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	if cs == nil {
+		return nil, newError(ErrCodeInvalidRequest, "graph.subscribe requires a persistent transport (WebSocket or Unix socket)")
+	}
+	var p struct {
+		Kind   string              `json:"kind"`
+		Filter SubscriptionFilter `json:"filter"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	switch p.Kind {
+	case SubscribeNodes, SubscribeEdges, SubscribeNeighborhood:
+	default:
+		return nil, newError(ErrCodeInvalidParams, "unknown subscription kind: "+p.Kind)
+	}
+	if err := p.Filter.compile(); err != nil {
+		return nil, newError(ErrCodeInvalidParams, "invalid labelRegex: "+err.Error())
+	}
+	if p.Kind != SubscribeNeighborhood && p.Filter.Hops != 0 {
+		return nil, newError(ErrCodeInvalidParams, "hops is only valid for neighborhood subscriptions")
+	}
+
+	nodeIDs := p.Filter.nodeIDSet()
+	if p.Kind == SubscribeNeighborhood && p.Filter.Hops > 0 && nodeIDs != nil {
+		nodeIDs = g.Neighborhood(p.Filter.NodeIDs, p.Filter.Hops)
+	}
+
+	sub := &Subscription{
+		ID:      newSubscriptionID(),
+		Kind:    p.Kind,
+		Filter:  p.Filter,
+		nodeIDs: nodeIDs,
+	}
+	cs.add(sub)
+	return sub.ID, nil
+}
+
+func handleNearest(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Point spatial.Point `json:"point"`
+		K     int           `json:"k"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	return g.Nearest(p.Point, p.K), nil
+}
+
+func handleRadius(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Point  spatial.Point `json:"point"`
+		Radius float64       `json:"radius"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	return g.Radius(p.Point, p.Radius), nil
+}
+
+func handleBox(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	var p struct {
+		Min spatial.Point `json:"min"`
+		Max spatial.Point `json:"max"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	return g.Box(p.Min, p.Max), nil
+}
+
+// handleAdminSnapshot snapshots the Graph's configured store and returns
+// the snapshot bytes base64-encoded, since JSON-RPC results must be
+// JSON-marshalable and Snapshot deals in raw bytes.
+func handleAdminSnapshot(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	if g.persist == nil {
+		return nil, newError(ErrCodeNoPersistence, "no persistence store configured")
+	}
+	var buf bytes.Buffer
+	if err := g.persist.Snapshot(&buf); err != nil {
+		return nil, newError(ErrCodeInternal, err.Error())
+	}
+	return map[string]string{"snapshot": base64.StdEncoding.EncodeToString(buf.Bytes())}, nil
+}
+
+// handleAdminRestore replaces the Graph's persisted and in-memory state
+// with a prior admin.snapshot result, then rehydrates the Graph the same
+// way SetStore does on startup.
+func handleAdminRestore(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	if g.persist == nil {
+		return nil, newError(ErrCodeNoPersistence, "no persistence store configured")
+	}
+	var p struct {
+		Snapshot string `json:"snapshot"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	data, derr := base64.StdEncoding.DecodeString(p.Snapshot)
+	if derr != nil {
+		return nil, newError(ErrCodeInvalidParams, "snapshot: "+derr.Error())
+	}
+	if err := g.persist.Restore(bytes.NewReader(data)); err != nil {
+		return nil, newError(ErrCodeInternal, err.Error())
+	}
+	if err := g.SetStore(g.persist); err != nil {
+		return nil, newError(ErrCodeInternal, err.Error())
+	}
+	return true, nil
+}
+
+func handleUnsubscribe(g *Graph, cs *connState, params json.RawMessage) (any, *Error) {
+	if cs == nil {
+		return nil, newError(ErrCodeInvalidRequest, "graph.unsubscribe requires a persistent transport (WebSocket or Unix socket)")
+	}
+	var p struct {
+		ID string `json:"id"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	return cs.remove(p.ID), nil
+}
@@ -0,0 +1,84 @@
+// Package rpc implements a JSON-RPC 2.0 service over schema.Node and
+// schema.HyperEdge, reachable over plain HTTP POST and over a WebSocket
+// upgrade on the same handler.
+package rpc
+
+import "encoding/json"
+
+// Version is the only JSON-RPC version this server accepts.
+const Version = "2.0"
+
+// Standard JSON-RPC 2.0 error codes, per the spec.
+const (
+	ErrCodeParse          = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+)
+
+// Application errors live in the namespaced range from -32000 downward, as
+// recommended by the spec for server-defined errors.
+const (
+	ErrCodeNotFound      = -32000
+	ErrCodeValidation    = -32001
+	ErrCodeBadFilter     = -32002
+	ErrCodeNoPersistence = -32003
+)
+
+// Request is a single JSON-RPC 2.0 request object. An absent ID marks the
+// request as a notification: the server executes it but never replies.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// IsNotification reports whether r carries no id, per the JSON-RPC 2.0
+// definition of a notification.
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response is a single JSON-RPC 2.0 response object. Result and Error are
+// mutually exclusive, as required by the spec.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func newError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func newErrorData(code int, message string, data any) *Error {
+	return &Error{Code: code, Message: message, Data: data}
+}
+
+// newResult builds a successful Response for id, marshaling result into
+// the Result field. If result itself is unmarshalable, which would be a
+// programmer error in a method handler, it returns an ErrCodeInternal
+// failure for id instead.
+func newResult(id json.RawMessage, result any) *Response {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return &Response{JSONRPC: Version, ID: id, Error: newError(ErrCodeInternal, err.Error())}
+	}
+	return &Response{JSONRPC: Version, ID: id, Result: b}
+}
+
+func newFailure(id json.RawMessage, err *Error) *Response {
+	return &Response{JSONRPC: Version, ID: id, Error: err}
+}
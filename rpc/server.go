@@ -0,0 +1,235 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// DefaultMaxRequestContentLength is the default ceiling on an incoming
+// request body, matching go-ethereum's maxRequestContentLength.
+const DefaultMaxRequestContentLength = 128 * 1024
+
+// DefaultSubscriberHighWater is the default number of queued outbound
+// messages a WebSocket subscriber may fall behind by before the server
+// drops its connection.
+const DefaultSubscriberHighWater = 256
+
+// Server serves the graph.* JSON-RPC 2.0 method set over both HTTP POST
+// and a WebSocket upgrade, sharing a single Graph and a single Dispatch
+// path; the wire codec is negotiated per HTTP request or per WebSocket
+// connection.
+// WebSocket connections additionally get a subscription registry for
+// graph.subscribe/graph.unsubscribe; this works unchanged over a Unix
+// socket listener, since the upgrade only needs a net.Conn to hijack.
+type Server struct {
+	graph *Graph
+
+	// MaxRequestContentLength bounds a single HTTP body or WS frame. Zero
+	// falls back to DefaultMaxRequestContentLength.
+	MaxRequestContentLength int64
+
+	// SubscriberHighWater bounds a WebSocket connection's outbound queue.
+	// Zero falls back to DefaultSubscriberHighWater.
+	SubscriberHighWater int
+
+	upgrader websocket.Upgrader
+}
+
+// NewServer returns a Server backed by a fresh, empty Graph.
+func NewServer() *Server {
+	return &Server{
+		graph:                   NewGraph(),
+		MaxRequestContentLength: DefaultMaxRequestContentLength,
+		SubscriberHighWater:     DefaultSubscriberHighWater,
+		upgrader:                websocket.Upgrader{Subprotocols: wsSubprotocols()},
+	}
+}
+
+func (s *Server) highWater() int {
+	if s.SubscriberHighWater > 0 {
+		return s.SubscriberHighWater
+	}
+	return DefaultSubscriberHighWater
+}
+
+func (s *Server) maxLen() int64 {
+	if s.MaxRequestContentLength > 0 {
+		return s.MaxRequestContentLength
+	}
+	return DefaultMaxRequestContentLength
+}
+
+// ServeHTTP dispatches to the WebSocket handler when the request carries
+// an Upgrade: websocket header, and to the plain HTTP POST handler
+// otherwise. Both paths share Dispatch.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWS(w, r)
+		return
+	}
+	s.serveHTTP(w, r)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, s.maxLen()+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if int64(len(body)) > s.maxLen() {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	codec := negotiateHTTPCodec(r)
+	out := s.Dispatch(body, codec, nil)
+	w.Header().Set("Content-Type", codec.MIME())
+	if out == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	_, _ = w.Write(out)
+}
+
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(s.maxLen())
+
+	codec := schema.CodecByName(conn.Subprotocol())
+	if codec == nil {
+		codec = schema.DefaultCodec
+	}
+
+	cs := newConnState(conn, codec, s.highWater())
+	defer cs.shutdown()
+
+	events, cancelWatch := s.graph.Watch(s.highWater())
+	defer cancelWatch()
+	go func() {
+		for ev := range events {
+			cs.dispatch(ev)
+		}
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		out := s.Dispatch(msg, codec, cs)
+		if out == nil {
+			continue
+		}
+		cs.enqueue(out)
+	}
+}
+
+// Dispatch decodes a raw JSON-RPC payload encoded with codec (a single
+// request object or a batch array), executes every call against the
+// shared Graph, and returns the response re-encoded with codec. It
+// returns nil when there is nothing to send back, which happens when
+// the payload is a single notification or a batch made up entirely of
+// notifications. cs is the calling connection's subscription state, or
+// nil over a non-persistent transport such as HTTP POST.
+func (s *Server) Dispatch(raw []byte, codec schema.Codec, cs *connState) []byte {
+	jsonRaw, err := transcode(raw, codec, schema.DefaultCodec)
+	if err != nil {
+		out, _ := transcode(encode(newFailure(nil, newError(ErrCodeParse, err.Error()))), schema.DefaultCodec, codec)
+		return out
+	}
+	jsonResp := s.dispatchJSON(jsonRaw, cs)
+	if jsonResp == nil {
+		return nil
+	}
+	out, err := transcode(jsonResp, schema.DefaultCodec, codec)
+	if err != nil {
+		out, _ = transcode(encode(newFailure(nil, newError(ErrCodeInternal, err.Error()))), schema.DefaultCodec, codec)
+	}
+	return out
+}
+
+// dispatchJSON runs the JSON-RPC envelope logic over JSON-encoded bytes;
+// Dispatch transcodes to and from the negotiated wire codec around it.
+func (s *Server) dispatchJSON(raw []byte, cs *connState) []byte {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return encode(newFailure(nil, newError(ErrCodeInvalidRequest, "empty request")))
+	}
+
+	if trimmed[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			return encode(newFailure(nil, newError(ErrCodeParse, err.Error())))
+		}
+		if len(reqs) == 0 {
+			return encode(newFailure(nil, newError(ErrCodeInvalidRequest, "empty batch")))
+		}
+		var resps []*Response
+		for _, r := range reqs {
+			if resp := s.dispatchOne(r, cs); resp != nil {
+				resps = append(resps, resp)
+			}
+		}
+		if len(resps) == 0 {
+			return nil
+		}
+		return encode(resps)
+	}
+
+	resp := s.dispatchOne(trimmed, cs)
+	if resp == nil {
+		return nil
+	}
+	return encode(resp)
+}
+
+// dispatchOne executes a single JSON-RPC request object, returning nil
+// for a well-formed notification.
+func (s *Server) dispatchOne(raw json.RawMessage, cs *connState) *Response {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return newFailure(nil, newError(ErrCodeParse, err.Error()))
+	}
+	if req.JSONRPC != Version {
+		return newFailure(req.ID, newError(ErrCodeInvalidRequest, "jsonrpc must be \"2.0\""))
+	}
+
+	handler, ok := methods[req.Method]
+	if !ok {
+		if req.IsNotification() {
+			return nil
+		}
+		return newFailure(req.ID, newError(ErrCodeMethodNotFound, "method not found: "+req.Method))
+	}
+
+	result, rpcErr := handler(s.graph, cs, req.Params)
+	if req.IsNotification() {
+		return nil
+	}
+	if rpcErr != nil {
+		return newFailure(req.ID, rpcErr)
+	}
+	return newResult(req.ID, result)
+}
+
+func encode(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// Marshaling our own Response/Error types cannot fail.
+		panic(err)
+	}
+	return b
+}
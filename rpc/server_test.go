@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+func TestDispatchAddAndGetNode(t *testing.T) {
+	s := NewServer()
+
+	addReq := `{"jsonrpc":"2.0","method":"graph.addNode","params":{"id":"n1","title":"Alice","position":[0,0,0,0]},"id":1}`
+	out := s.Dispatch([]byte(addReq), schema.DefaultCodec, nil)
+	var resp Response
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+
+	getReq := `{"jsonrpc":"2.0","method":"graph.getNode","params":{"id":"n1"},"id":2}`
+	out = s.Dispatch([]byte(getReq), schema.DefaultCodec, nil)
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+}
+
+func TestDispatchNotificationHasNoResponse(t *testing.T) {
+	s := NewServer()
+	out := s.Dispatch([]byte(`{"jsonrpc":"2.0","method":"graph.getNode","params":{"id":"missing"}}`), schema.DefaultCodec, nil)
+	if out != nil {
+		t.Fatalf("expected no response for a notification, got %s", out)
+	}
+}
+
+func TestDispatchMethodNotFound(t *testing.T) {
+	s := NewServer()
+	out := s.Dispatch([]byte(`{"jsonrpc":"2.0","method":"graph.bogus","id":1}`), schema.DefaultCodec, nil)
+	var resp Response
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != ErrCodeMethodNotFound {
+		t.Fatalf("expected method-not-found error, got %+v", resp.Error)
+	}
+}
+
+func TestDispatchBatch(t *testing.T) {
+	s := NewServer()
+	batch := `[
+		{"jsonrpc":"2.0","method":"graph.addNode","params":{"id":"n1","title":"Alice","position":[0,0,0,0]},"id":1},
+		{"jsonrpc":"2.0","method":"graph.addNode","params":{"id":"n2","title":"Bob","position":[0,0,0,0]},"id":2}
+	]`
+	out := s.Dispatch([]byte(batch), schema.DefaultCodec, nil)
+	var resps []Response
+	if err := json.Unmarshal(out, &resps); err != nil {
+		t.Fatalf("decode batch response: %v", err)
+	}
+	if len(resps) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(resps))
+	}
+}
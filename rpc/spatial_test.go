@@ -0,0 +1,30 @@
+package rpc
+
+import (
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+func TestGraphNearestTracksNodeMutations(t *testing.T) {
+	g := NewGraph()
+	if err := g.AddNode(schema.Node{Id: "a", Position: [4]float64{0, 0, 0, 0}}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if err := g.AddNode(schema.Node{Id: "b", Position: [4]float64{10, 0, 0, 0}}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	got := g.Nearest([4]float64{0, 0, 0, 0}, 1)
+	if len(got) != 1 || got[0].Id != "a" {
+		t.Fatalf("expected node a nearest, got %+v", got)
+	}
+
+	if err := g.DeleteNode("a"); err != nil {
+		t.Fatalf("DeleteNode: %v", err)
+	}
+	got = g.Nearest([4]float64{0, 0, 0, 0}, 1)
+	if len(got) != 1 || got[0].Id != "b" {
+		t.Fatalf("expected node b nearest after deleting a, got %+v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"path/filepath"
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+	rstore "github.com/Imnsol/rRPC/examples/schema-generated/go/store"
+)
+
+func TestGraphSetStoreHydratesAndWritesThrough(t *testing.T) {
+	dir := t.TempDir()
+	st, err := rstore.OpenPersistent(filepath.Join(dir, "wal.db"), filepath.Join(dir, "snapshot"), nil)
+	if err != nil {
+		t.Fatalf("OpenPersistent: %v", err)
+	}
+	defer st.Close()
+
+	g := NewGraph()
+	if err := g.SetStore(st); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	if err := g.AddNode(schema.Node{Id: "a", Title: "Alice"}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	if _, ok, _ := st.GetNode("a"); !ok {
+		t.Fatalf("expected AddNode to write through to the store")
+	}
+
+	g2 := NewGraph()
+	if err := g2.SetStore(st); err != nil {
+		t.Fatalf("SetStore on fresh Graph: %v", err)
+	}
+	if n, ok := g2.GetNode("a"); !ok || n.Title != "Alice" {
+		t.Fatalf("expected fresh Graph to hydrate from the store, got %+v ok=%v", n, ok)
+	}
+}
+
+func TestHandleAdminSnapshotRestoreRoundtrip(t *testing.T) {
+	dir := t.TempDir()
+	st, err := rstore.OpenPersistent(filepath.Join(dir, "wal.db"), filepath.Join(dir, "snapshot"), nil)
+	if err != nil {
+		t.Fatalf("OpenPersistent: %v", err)
+	}
+	defer st.Close()
+
+	g := NewGraph()
+	if err := g.SetStore(st); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	if err := g.AddNode(schema.Node{Id: "a", Title: "Alice"}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	snapResult, rpcErr := handleAdminSnapshot(g, nil, nil)
+	if rpcErr != nil {
+		t.Fatalf("handleAdminSnapshot: %v", rpcErr)
+	}
+
+	if err := g.AddNode(schema.Node{Id: "b", Title: "Bob"}); err != nil {
+		t.Fatalf("AddNode b: %v", err)
+	}
+
+	snap := snapResult.(map[string]string)["snapshot"]
+	params := []byte(`{"snapshot":"` + snap + `"}`)
+	if _, rpcErr := handleAdminRestore(g, nil, params); rpcErr != nil {
+		t.Fatalf("handleAdminRestore: %v", rpcErr)
+	}
+
+	if _, ok := g.GetNode("b"); ok {
+		t.Fatalf("expected node b to be gone after restoring a pre-b snapshot")
+	}
+	if n, ok := g.GetNode("a"); !ok || n.Title != "Alice" {
+		t.Fatalf("expected node a to survive restore, got %+v ok=%v", n, ok)
+	}
+}
@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"encoding/json"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+// writeWait bounds how long a close control frame may take to flush
+// before the connection is abandoned outright.
+const writeWait = 5 * time.Second
+
+// Subscription kinds accepted by graph.subscribe.
+const (
+	SubscribeNodes        = "nodes"
+	SubscribeEdges        = "edges"
+	SubscribeNeighborhood = "neighborhood"
+)
+
+// CloseSlowSubscriber is the WebSocket close code sent to a connection
+// whose outbound queue exceeds its high-water mark. It falls in the
+// library/framework-defined range (4000-4999) reserved by RFC 6455.
+const CloseSlowSubscriber = 4008
+
+// SubscriptionFilter narrows which events a subscription receives.
+type SubscriptionFilter struct {
+	// NodeIDs restricts "nodes" and "neighborhood" subscriptions to
+	// events about these node ids. Empty means no restriction.
+	NodeIDs []string `json:"nodeIds,omitempty"`
+	// LabelRegex restricts "edges" subscriptions to edges whose Label.Kind
+	// matches this regular expression (an edge with no Label matches as
+	// ""). Empty means no restriction.
+	LabelRegex string `json:"labelRegex,omitempty"`
+	// Hops bounds how many hyperedge hops a "neighborhood" subscription
+	// follows from NodeIDs before an event is considered out of scope.
+	Hops int `json:"hops,omitempty"`
+
+	labelRe *regexp.Regexp
+}
+
+func (f *SubscriptionFilter) compile() error {
+	if f.LabelRegex == "" {
+		return nil
+	}
+	re, err := regexp.Compile(f.LabelRegex)
+	if err != nil {
+		return err
+	}
+	f.labelRe = re
+	return nil
+}
+
+func (f *SubscriptionFilter) nodeIDSet() map[string]bool {
+	if len(f.NodeIDs) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(f.NodeIDs))
+	for _, id := range f.NodeIDs {
+		set[id] = true
+	}
+	return set
+}
+
+// subscriptionNotification is the params payload of a graph.subscription
+// notification.
+type subscriptionNotification struct {
+	Subscription string `json:"subscription"`
+	Kind         string `json:"kind"`
+	Action       string `json:"action"`
+	Result       any    `json:"result"`
+}
+
+// Subscription is one live graph.subscribe call on a connection.
+type Subscription struct {
+	ID      string
+	Kind    string
+	Filter  SubscriptionFilter
+	nodeIDs map[string]bool
+}
+
+// matches reports whether ev falls within s's Kind and Filter.
+func (s *Subscription) matches(ev Event) bool {
+	switch s.Kind {
+	case SubscribeNodes:
+		if ev.Kind != EventKindNode {
+			return false
+		}
+		return s.nodeIDs == nil || s.nodeIDs[ev.Node.Id]
+	case SubscribeEdges:
+		if ev.Kind != EventKindEdge {
+			return false
+		}
+		if s.Filter.labelRe != nil {
+			kind := ""
+			if ev.Edge.Label != nil {
+				kind = ev.Edge.Label.Kind
+			}
+			if !s.Filter.labelRe.MatchString(kind) {
+				return false
+			}
+		}
+		return true
+	case SubscribeNeighborhood:
+		// s.nodeIDs is the seed set expanded out to Filter.Hops by
+		// Graph.Neighborhood at subscribe time, so this is still a
+		// direct membership check against that (possibly hop-expanded)
+		// set rather than a live walk per event.
+		if s.nodeIDs == nil {
+			return true
+		}
+		switch ev.Kind {
+		case EventKindNode:
+			return s.nodeIDs[ev.Node.Id]
+		case EventKindEdge:
+			for _, nid := range ev.Edge.Nodes {
+				if s.nodeIDs[nid] {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// connState tracks the subscriptions and outbound queue for a single
+// persistent connection (WebSocket, including one accepted over a Unix
+// socket listener).
+type connState struct {
+	subsMu sync.Mutex
+	subs   map[string]*Subscription
+
+	codec     schema.Codec
+	outbound  chan []byte
+	highWater int
+	conn      *websocket.Conn
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newConnState(conn *websocket.Conn, codec schema.Codec, highWater int) *connState {
+	cs := &connState{
+		subs:      make(map[string]*Subscription),
+		codec:     codec,
+		outbound:  make(chan []byte, highWater),
+		highWater: highWater,
+		conn:      conn,
+		closed:    make(chan struct{}),
+	}
+	go cs.writeLoop()
+	return cs
+}
+
+// shutdown stops the write loop. It is safe to call more than once and
+// from dropSlow or from the connection's read loop on normal exit.
+func (cs *connState) shutdown() {
+	cs.closeOnce.Do(func() { close(cs.closed) })
+}
+
+func (cs *connState) writeLoop() {
+	for {
+		select {
+		case msg, ok := <-cs.outbound:
+			if !ok {
+				return
+			}
+			if err := cs.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-cs.closed:
+			return
+		}
+	}
+}
+
+// enqueue delivers msg to the connection's outbound queue. If the queue
+// is already at its high-water mark — the client isn't draining fast
+// enough — the connection is dropped with CloseSlowSubscriber instead of
+// blocking the broadcaster.
+func (cs *connState) enqueue(msg []byte) {
+	select {
+	case cs.outbound <- msg:
+	default:
+		cs.dropSlow()
+	}
+}
+
+func (cs *connState) dropSlow() {
+	select {
+	case <-cs.closed:
+		return
+	default:
+	}
+	cs.shutdown()
+	closeMsg := websocket.FormatCloseMessage(CloseSlowSubscriber, "subscriber outbound queue exceeded high-water mark")
+	_ = cs.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeWait))
+	_ = cs.conn.Close()
+}
+
+func (cs *connState) add(sub *Subscription) {
+	cs.subsMu.Lock()
+	defer cs.subsMu.Unlock()
+	cs.subs[sub.ID] = sub
+}
+
+func (cs *connState) remove(id string) bool {
+	cs.subsMu.Lock()
+	defer cs.subsMu.Unlock()
+	if _, ok := cs.subs[id]; !ok {
+		return false
+	}
+	delete(cs.subs, id)
+	return true
+}
+
+// dispatch pushes ev, as a graph.subscription notification, to every
+// subscription on cs whose filter matches.
+func (cs *connState) dispatch(ev Event) {
+	cs.subsMu.Lock()
+	var notes []subscriptionNotification
+	for _, sub := range cs.subs {
+		if sub.matches(ev) {
+			var result any
+			switch ev.Kind {
+			case EventKindNode:
+				result = ev.Node
+			case EventKindEdge:
+				result = ev.Edge
+			}
+			notes = append(notes, subscriptionNotification{
+				Subscription: sub.ID,
+				Kind:         ev.Kind,
+				Action:       ev.Action,
+				Result:       result,
+			})
+		}
+	}
+	cs.subsMu.Unlock()
+
+	for _, n := range notes {
+		b, err := json.Marshal(struct {
+			JSONRPC string                   `json:"jsonrpc"`
+			Method  string                   `json:"method"`
+			Params  subscriptionNotification `json:"params"`
+		}{JSONRPC: Version, Method: "graph.subscription", Params: n})
+		if err != nil {
+			continue
+		}
+		if out, err := transcode(b, schema.DefaultCodec, cs.codec); err == nil {
+			cs.enqueue(out)
+		}
+	}
+}
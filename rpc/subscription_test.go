@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"testing"
+
+	schema "github.com/Imnsol/rRPC/examples/schema-generated/go"
+)
+
+func TestSubscribeRequiresPersistentTransport(t *testing.T) {
+	s := NewServer()
+	_, rpcErr := handleSubscribe(s.graph, nil, []byte(`{"kind":"nodes"}`))
+	if rpcErr == nil {
+		t.Fatal("expected an error when subscribing without a connection")
+	}
+}
+
+func TestSubscriptionFilterMatchesNodeIDs(t *testing.T) {
+	sub := &Subscription{Kind: SubscribeNodes, nodeIDs: map[string]bool{"n1": true}}
+	n1 := schema.Node{Id: "n1"}
+	n2 := schema.Node{Id: "n2"}
+	if !sub.matches(Event{Kind: EventKindNode, Action: EventCreated, Node: &n1}) {
+		t.Fatal("expected a match for a subscribed node id")
+	}
+	if sub.matches(Event{Kind: EventKindNode, Action: EventCreated, Node: &n2}) {
+		t.Fatal("expected no match for an unsubscribed node id")
+	}
+}
+
+func TestGraphNeighborhoodExpandsByHops(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if err := g.AddNode(schema.Node{Id: id}); err != nil {
+			t.Fatalf("AddNode %s: %v", id, err)
+		}
+	}
+	// a -- b -- c -- d
+	if err := g.AddEdge(schema.HyperEdge{Id: "e1", Nodes: []string{"a", "b"}}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	if err := g.AddEdge(schema.HyperEdge{Id: "e2", Nodes: []string{"b", "c"}}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	if err := g.AddEdge(schema.HyperEdge{Id: "e3", Nodes: []string{"c", "d"}}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+
+	got := g.Neighborhood([]string{"a"}, 2)
+	want := map[string]bool{"a": true, "b": true, "c": true}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for id := range want {
+		if !got[id] {
+			t.Fatalf("expected %q in 2-hop neighborhood, got %v", id, got)
+		}
+	}
+	if got["d"] {
+		t.Fatalf("expected %q outside a 2-hop neighborhood, got %v", "d", got)
+	}
+}
+
+func TestSubscribeNeighborhoodHopsExpandsSubscription(t *testing.T) {
+	s := NewServer()
+	if err := s.graph.AddNode(schema.Node{Id: "a"}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if err := s.graph.AddNode(schema.Node{Id: "b"}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if err := s.graph.AddEdge(schema.HyperEdge{Id: "e1", Nodes: []string{"a", "b"}}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+
+	cs := newConnState(nil, schema.DefaultCodec, 1)
+	defer cs.shutdown()
+	params := []byte(`{"kind":"neighborhood","filter":{"nodeIds":["a"],"hops":1}}`)
+	id, rpcErr := handleSubscribe(s.graph, cs, params)
+	if rpcErr != nil {
+		t.Fatalf("handleSubscribe: %v", rpcErr)
+	}
+	sub := cs.subs[id.(string)]
+	n2 := schema.Node{Id: "b"}
+	if !sub.matches(Event{Kind: EventKindNode, Action: EventCreated, Node: &n2}) {
+		t.Fatal("expected 1-hop neighbor b to be in scope")
+	}
+}
+
+func TestSubscribeRejectsHopsOutsideNeighborhood(t *testing.T) {
+	s := NewServer()
+	cs := newConnState(nil, schema.DefaultCodec, 1)
+	defer cs.shutdown()
+	params := []byte(`{"kind":"nodes","filter":{"hops":1}}`)
+	if _, rpcErr := handleSubscribe(s.graph, cs, params); rpcErr == nil {
+		t.Fatal("expected an error for hops on a non-neighborhood subscription")
+	}
+}
+
+func TestSubscriptionFilterLabelRegex(t *testing.T) {
+	f := SubscriptionFilter{LabelRegex: "^friend-.*"}
+	if err := f.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	sub := &Subscription{Kind: SubscribeEdges, Filter: f}
+	match := schema.HyperEdge{Id: "e1", Label: &schema.Label{Kind: "friend-of"}}
+	noMatch := schema.HyperEdge{Id: "e2", Label: &schema.Label{Kind: "enemy-of"}}
+	if !sub.matches(Event{Kind: EventKindEdge, Action: EventCreated, Edge: &match}) {
+		t.Fatal("expected label regex to match")
+	}
+	if sub.matches(Event{Kind: EventKindEdge, Action: EventCreated, Edge: &noMatch}) {
+		t.Fatal("expected label regex not to match")
+	}
+}